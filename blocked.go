@@ -0,0 +1,157 @@
+package bloom
+
+// DefaultBlockBits is the default size, in bits, of a single block in a
+// BlockedFilter. 512 bits is 64 bytes, matching a common CPU cache line, so
+// that every probe for a given element touches at most one cache line.
+const DefaultBlockBits = 512
+
+// A BlockedFilter is a Bloom filter whose bit array is partitioned into
+// fixed-size blocks, with every hash probe for a given element confined to a
+// single block. This sacrifices a small amount of accuracy relative to a
+// classic Filter of the same size, in exchange for touching only one cache
+// line per Add/Test instead of k scattered words across the whole array.
+type BlockedFilter struct {
+	blockBits uint
+	numBlocks uint
+	k         uint
+	b         []uint64
+}
+
+// NewBlocked creates a BlockedFilter sized for n items at a target false
+// positive rate of fp, using DefaultBlockBits-sized blocks.
+func NewBlocked(n uint, fp float64) *BlockedFilter {
+	return NewBlockedWithBlockBits(n, fp, DefaultBlockBits)
+}
+
+// NewBlockedWithBlockBits creates a BlockedFilter sized for n items at a
+// target false positive rate of fp, using blocks of blockBits bits each.
+// blockBits should be a multiple of 64; 512 and 256 are the common choices,
+// corresponding to one or half of a typical 64-byte cache line.
+func NewBlockedWithBlockBits(n uint, fp float64, blockBits uint) *BlockedFilter {
+	blockBits = ((blockBits + 63) / 64) * 64
+	if blockBits < 64 {
+		blockBits = 64
+	}
+	m, k := EstimateParameters(n, fp)
+	numBlocks := (m + blockBits - 1) / blockBits
+	if numBlocks < 1 {
+		numBlocks = 1
+	}
+	return &BlockedFilter{
+		blockBits: blockBits,
+		numBlocks: numBlocks,
+		k:         k,
+		b:         make([]uint64, numBlocks*blockBits/64),
+	}
+}
+
+// blockAndPositions returns the block index and the k in-block bit positions
+// for data. The block is chosen from one pair of hash lanes and the in-block
+// positions are double-hashed from an independent pair (via hashesFor,
+// the same helper Filter itself uses) — reusing the block-selection lanes
+// for the in-block positions would make every probe collapse onto a single
+// bit whenever blockBits divides numBlocks, since fixing "v mod numBlocks"
+// also fixes "v mod blockBits" in that case.
+func (f *BlockedFilter) blockAndPositions(data []byte) (uint, []uint) {
+	h := hashesFor(defaultHasher, data)
+
+	block := uint(h[0] % uint64(f.numBlocks))
+
+	positions := make([]uint, f.k)
+	for i := uint(0); i < f.k; i++ {
+		positions[i] = uint((h[2] + uint64(i)*h[3]) % uint64(f.blockBits))
+	}
+	return block, positions
+}
+
+func (f *BlockedFilter) bitIndex(block, pos uint) uint {
+	return block*f.blockBits + pos
+}
+
+// Add inserts data into the filter. It returns f so calls can be chained.
+func (f *BlockedFilter) Add(data []byte) *BlockedFilter {
+	block, positions := f.blockAndPositions(data)
+	for _, pos := range positions {
+		i := f.bitIndex(block, pos)
+		f.b[i/64] |= 1 << (i % 64)
+	}
+	return f
+}
+
+// Test reports whether data is (probably) in the filter. As with Filter,
+// false positives are possible but false negatives are not.
+func (f *BlockedFilter) Test(data []byte) bool {
+	block, positions := f.blockAndPositions(data)
+	for _, pos := range positions {
+		i := f.bitIndex(block, pos)
+		if f.b[i/64]&(1<<(i%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// NumBlocks returns the number of blocks in the filter.
+func (f *BlockedFilter) NumBlocks() uint {
+	return f.numBlocks
+}
+
+// BlockBits returns the size, in bits, of a single block.
+func (f *BlockedFilter) BlockBits() uint {
+	return f.blockBits
+}
+
+// K returns the number of hashing functions used by the filter.
+func (f *BlockedFilter) K() uint {
+	return f.k
+}
+
+const blockedMagic = "BLKF"
+const blockedVersion = uint32(1)
+
+// MarshalBinary encodes the filter into a versioned binary format: 4-byte
+// magic "BLKF", uint32 version, uint64 blockBits, uint64 numBlocks, uint32 k,
+// then the packed bit array as little-endian uint64 words.
+func (f *BlockedFilter) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, 4+4+8+8+4+len(f.b)*8)
+	buf = append(buf, blockedMagic...)
+	buf = appendUint32(buf, blockedVersion)
+	buf = appendUint64(buf, uint64(f.blockBits))
+	buf = appendUint64(buf, uint64(f.numBlocks))
+	buf = appendUint32(buf, uint32(f.k))
+	for _, w := range f.b {
+		buf = appendUint64(buf, w)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a filter previously encoded with MarshalBinary.
+func (f *BlockedFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 4+4+8+8+4 || string(data[:4]) != blockedMagic {
+		return errInvalidEncoding
+	}
+	data = data[4:]
+	version, data := readUint32(data)
+	if version != blockedVersion {
+		return errUnsupportedVersion
+	}
+	blockBits, data := readUint64(data)
+	numBlocks, data := readUint64(data)
+	k, data := readUint32(data)
+
+	wordCount := blockBits * numBlocks / 64
+	if uint64(len(data)) != wordCount*8 {
+		return errInvalidEncoding
+	}
+
+	f.blockBits = uint(blockBits)
+	f.numBlocks = uint(numBlocks)
+	f.k = uint(k)
+	f.b = make([]uint64, wordCount)
+	for i := range f.b {
+		var w uint64
+		w, data = readUint64(data)
+		f.b[i] = w
+	}
+	return nil
+}
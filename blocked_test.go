@@ -0,0 +1,135 @@
+package bloom
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/willf/bloom/stats"
+)
+
+// blockedLocations returns BlockedFilter.blockAndPositions for data on a
+// filter of the given shape without allocating its bit array, so the test
+// below can pick out the elements that land in one block and check the
+// uniformity of their in-block positions with stats.ChiSquare.
+func blockedLocations(data []byte, k, blockBits uint32, numBlocks uint) (uint, []uint) {
+	f := &BlockedFilter{blockBits: uint(blockBits), numBlocks: numBlocks, k: uint(k)}
+	return f.blockAndPositions(data)
+}
+
+// TestBlockedLocationUniformity checks that, restricted to a single block,
+// the in-block bit positions produced by BlockedFilter are uniformly
+// distributed, analogous to TestBloomLocationUniformity for the classic
+// filter. As there, a chi-squared p-value below 0.001 fails the test
+// outright rather than being left for a human to eyeball.
+func TestBlockedLocationUniformity(t *testing.T) {
+	var blockBits uint32 = 8
+	var k uint32 = 3
+	var numBlocks uint = 1024
+	var rounds uint32 = 4000000
+
+	elements := make([][]byte, rounds)
+	for x := uint32(0); x < rounds; x++ {
+		ctrlist := make([]uint8, 4)
+		ctrlist[0] = uint8(x)
+		ctrlist[1] = uint8(x >> 8)
+		ctrlist[2] = uint8(x >> 16)
+		ctrlist[3] = uint8(x >> 24)
+		elements[x] = ctrlist
+	}
+
+	target := uint(0)
+	var chosen [][]byte
+	for _, data := range elements {
+		block, _ := blockedLocations(data, k, blockBits, numBlocks)
+		if block == target {
+			chosen = append(chosen, data)
+		}
+	}
+
+	fmt.Printf("Blocked filter: %d of %d elements landed in block %d\n", len(chosen), rounds, target)
+
+	results := make([]uint, blockBits)
+	for _, data := range chosen {
+		_, positions := blockedLocations(data, k, blockBits, numBlocks)
+		for _, pos := range positions {
+			results[pos]++
+		}
+	}
+
+	e := float64(uint32(len(chosen))*k) / float64(blockBits)
+	result := stats.ChiSquare(results, e)
+
+	fmt.Printf("BlockedUniformityChiSquare\t%d\tchi2=%.4f\tdf=%.0f\tp=%.6f\n",
+		len(chosen), result.Statistic, result.DF, result.PValue)
+
+	if result.PValue < 0.001 {
+		t.Fatalf("uniformity regression: chi2=%.4f df=%.0f p=%.6f (want p >= 0.001)",
+			result.Statistic, result.DF, result.PValue)
+	}
+}
+
+func TestNewBlockedWithBlockBitsRoundsUpToMultipleOf64(t *testing.T) {
+	f := NewBlockedWithBlockBits(10000, 0.01, 100)
+	if f.BlockBits()%64 != 0 {
+		t.Fatalf("expected BlockBits to be a multiple of 64, got %d", f.BlockBits())
+	}
+
+	for i := 0; i < 2000; i++ {
+		f.Add([]byte(fmt.Sprintf("element-%d", i)))
+	}
+	for i := 0; i < 2000; i++ {
+		f.Test([]byte(fmt.Sprintf("element-%d", i)))
+	}
+}
+
+func TestBlockedFilterUnmarshalBinaryTruncated(t *testing.T) {
+	f := NewBlocked(1000, 0.01)
+	f.Add([]byte("alpha"))
+
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	var g BlockedFilter
+	if err := g.UnmarshalBinary(data[:len(data)-8]); err != errInvalidEncoding {
+		t.Fatalf("expected errInvalidEncoding for truncated data, got %v", err)
+	}
+}
+
+func BenchmarkBlockedFilterAdd(b *testing.B) {
+	f := NewBlocked(1000000, 0.01)
+	data := []byte("benchmark-element")
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		f.Add(data)
+	}
+}
+
+// BenchmarkClassicFilterAddLarge and BenchmarkBlockedFilterAddLarge size the
+// filter well beyond a typical L2 cache (tens of megabytes of bits) so the
+// classic filter's k scattered probes routinely miss cache, while the
+// blocked filter's probes stay confined to one line.
+func BenchmarkClassicFilterAddLarge(b *testing.B) {
+	f := NewWithEstimates(50000000, 0.01)
+	data := make([][]byte, 1000)
+	for i := range data {
+		data[i] = []byte(fmt.Sprintf("element-%d", i))
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		f.Add(data[n%len(data)])
+	}
+}
+
+func BenchmarkBlockedFilterAddLarge(b *testing.B) {
+	f := NewBlocked(50000000, 0.01)
+	data := make([][]byte, 1000)
+	for i := range data {
+		data[i] = []byte(fmt.Sprintf("element-%d", i))
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		f.Add(data[n%len(data)])
+	}
+}
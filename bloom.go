@@ -0,0 +1,145 @@
+// Package bloom implements Bloom filters, a space-efficient probabilistic
+// data structure conceived by Burton Howard Bloom in 1970, used to test set
+// membership with a tunable false-positive rate and no false negatives.
+package bloom
+
+import (
+	"math"
+)
+
+// A Filter is a Bloom filter with m bits and k hash functions.
+type Filter struct {
+	m      uint
+	k      uint
+	b      []uint64
+	n      uint64 // count of items added, tracked for serialization/inspection only
+	hasher Hasher
+}
+
+// New creates a new Bloom filter with m bits and k hashing functions, using
+// the package's built-in MurmurHash3 implementation.
+func New(m uint, k uint) *Filter {
+	if k < 1 {
+		k = 1
+	}
+	if m < 1 {
+		m = 1
+	}
+	return &Filter{m: m, k: k, b: make([]uint64, (m+63)/64), hasher: defaultHasher}
+}
+
+// NewWithHasher creates a new Bloom filter with m bits and k hashing
+// functions, deriving its hash lanes from h instead of the package's
+// built-in MurmurHash3 implementation. This lets callers plug in xxhash,
+// CityHash, SipHash, or any other Hasher.
+func NewWithHasher(m uint, k uint, h Hasher) *Filter {
+	f := New(m, k)
+	f.hasher = h
+	return f
+}
+
+// hashes returns the four 64-bit lanes f uses to place data, derived from
+// f.hasher.
+func (f *Filter) hashes(data []byte) [4]uint64 {
+	return hashesFor(f.hasher, data)
+}
+
+// location returns the ith bit location derived from h, for a filter of the
+// given size m.
+func location(h [4]uint64, i uint, m uint) uint {
+	ii := uint64(i)
+	return uint((h[ii%2] + ii*h[2+(((ii+(ii%2))%4)/2)]) % uint64(m))
+}
+
+func (f *Filter) location(h [4]uint64, i uint) uint {
+	return location(h, i, f.m)
+}
+
+func (f *Filter) set(i uint) {
+	f.b[i/64] |= 1 << (i % 64)
+}
+
+func (f *Filter) isSet(i uint) bool {
+	return f.b[i/64]&(1<<(i%64)) != 0
+}
+
+// Add inserts data into the filter. It returns f so calls can be chained.
+func (f *Filter) Add(data []byte) *Filter {
+	h := f.hashes(data)
+	for i := uint(0); i < f.k; i++ {
+		f.set(f.location(h, i))
+	}
+	f.n++
+	return f
+}
+
+// N returns the number of items that have been added to the filter.
+func (f *Filter) N() uint64 {
+	return f.n
+}
+
+// Test reports whether data is (probably) in the filter. False positives are
+// possible; false negatives are not.
+func (f *Filter) Test(data []byte) bool {
+	h := f.hashes(data)
+	for i := uint(0); i < f.k; i++ {
+		if !f.isSet(f.location(h, i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// M returns the number of bits in the filter.
+func (f *Filter) M() uint {
+	return f.m
+}
+
+// K returns the number of hashing functions used by the filter.
+func (f *Filter) K() uint {
+	return f.k
+}
+
+// Cap returns the capacity, in bits, of the underlying bit array. It is an
+// alias for M, kept for parity with the estimate-driven constructors where
+// "capacity" is the more natural term.
+func (f *Filter) Cap() uint {
+	return f.m
+}
+
+// EstimateParameters estimates the parameters m (number of bits in the
+// filter) and k (number of hashing functions) that minimise the false
+// positive rate for a filter expected to hold n items with a target false
+// positive rate of fp.
+//
+// The formulas are the standard ones:
+//
+//	m = ceil(-n * ln(fp) / ln(2)^2)
+//	k = round((m / n) * ln(2))
+func EstimateParameters(n uint, fp float64) (m uint, k uint) {
+	mf := math.Ceil(-1 * float64(n) * math.Log(fp) / math.Pow(math.Ln2, 2))
+	kf := math.Round((mf / float64(n)) * math.Ln2)
+
+	m = uint(mf)
+	if m < 1 {
+		m = 1
+	}
+	k = uint(kf)
+	if k < 1 {
+		k = 1
+	}
+	return m, k
+}
+
+// EstimateFalsePositiveRate estimates the false positive rate of a filter
+// with m bits, k hashing functions, after n items have been added.
+func EstimateFalsePositiveRate(m, k, n uint) float64 {
+	return math.Pow(1-math.Exp(-float64(k)*float64(n)/float64(m)), float64(k))
+}
+
+// NewWithEstimates creates a new Bloom filter sized for n items at a target
+// false positive rate of fp, using EstimateParameters to derive m and k.
+func NewWithEstimates(n uint, fp float64) *Filter {
+	m, k := EstimateParameters(n, fp)
+	return New(m, k)
+}
@@ -0,0 +1,42 @@
+package bloom
+
+import (
+	"testing"
+)
+
+func TestEstimateParameters(t *testing.T) {
+	m, k := EstimateParameters(1000000, 0.01)
+	if m == 0 {
+		t.Fatalf("expected m > 0, got %d", m)
+	}
+	if k < 1 {
+		t.Fatalf("expected k >= 1, got %d", k)
+	}
+}
+
+func TestNewWithEstimates(t *testing.T) {
+	f := NewWithEstimates(1000, 0.001)
+	if f.Cap() == 0 {
+		t.Fatalf("expected non-zero capacity")
+	}
+	if f.K() < 1 {
+		t.Fatalf("expected k >= 1, got %d", f.K())
+	}
+
+	f.Add([]byte("lollapalooza"))
+	if !f.Test([]byte("lollapalooza")) {
+		t.Errorf("expected lollapalooza to test true after being added")
+	}
+	if f.Test([]byte("something else entirely")) {
+		t.Errorf("did not expect unrelated item to test true")
+	}
+}
+
+func TestEstimateFalsePositiveRate(t *testing.T) {
+	n := uint(1000)
+	m, k := EstimateParameters(n, 0.01)
+	fp := EstimateFalsePositiveRate(m, k, n)
+	if fp <= 0 || fp >= 1 {
+		t.Fatalf("expected false positive rate in (0, 1), got %v", fp)
+	}
+}
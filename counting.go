@@ -0,0 +1,117 @@
+package bloom
+
+// maxCounterValue is the ceiling a CountingFilter's 4-bit counters saturate
+// at rather than overflow.
+const maxCounterValue = 15
+
+// A CountingFilter is a Bloom filter variant backed by small saturating
+// counters instead of single bits. In addition to Add and Test, it supports
+// Remove and an approximate Count per item. Counters are packed two to a
+// byte (4 bits each), the classic counting Bloom filter trade-off between
+// counter range and memory use.
+type CountingFilter struct {
+	m      uint
+	k      uint
+	counts []byte // packed 4-bit counters, two per byte
+	hasher Hasher
+}
+
+// NewCounting creates a new CountingFilter with m counters and k hashing
+// functions.
+func NewCounting(m uint, k uint) *CountingFilter {
+	if k < 1 {
+		k = 1
+	}
+	if m < 1 {
+		m = 1
+	}
+	return &CountingFilter{m: m, k: k, counts: make([]byte, (m+1)/2), hasher: defaultHasher}
+}
+
+// NewCountingWithEstimates creates a CountingFilter sized for n items at a
+// target false positive rate of fp, using the same sizing math as
+// NewWithEstimates.
+func NewCountingWithEstimates(n uint, fp float64) *CountingFilter {
+	m, k := EstimateParameters(n, fp)
+	return NewCounting(m, k)
+}
+
+// locations returns the k bit/counter locations data hashes to, reusing the
+// same location math as Filter.
+func (f *CountingFilter) locations(data []byte) []uint {
+	h := hashesFor(f.hasher, data)
+	locs := make([]uint, f.k)
+	for i := uint(0); i < f.k; i++ {
+		locs[i] = location(h, i, f.m)
+	}
+	return locs
+}
+
+func (f *CountingFilter) get(i uint) byte {
+	b := f.counts[i/2]
+	if i%2 == 0 {
+		return b & 0x0f
+	}
+	return b >> 4
+}
+
+func (f *CountingFilter) set(i uint, v byte) {
+	if v > maxCounterValue {
+		v = maxCounterValue
+	}
+	idx := i / 2
+	if i%2 == 0 {
+		f.counts[idx] = (f.counts[idx] & 0xf0) | v
+	} else {
+		f.counts[idx] = (f.counts[idx] & 0x0f) | (v << 4)
+	}
+}
+
+// Add increments the counters at data's locations, saturating at 15. It
+// returns f so calls can be chained.
+func (f *CountingFilter) Add(data []byte) *CountingFilter {
+	for _, loc := range f.locations(data) {
+		if c := f.get(loc); c < maxCounterValue {
+			f.set(loc, c+1)
+		}
+	}
+	return f
+}
+
+// Remove decrements the counters at data's locations, so a later Test can
+// reflect the removal. Removing an item that was never added (or removing
+// it more times than it was added) can produce false negatives for other
+// items that share a counter — the well-known caveat of counting Bloom
+// filters, and the price paid for supporting Remove at all.
+func (f *CountingFilter) Remove(data []byte) *CountingFilter {
+	for _, loc := range f.locations(data) {
+		if c := f.get(loc); c > 0 {
+			f.set(loc, c-1)
+		}
+	}
+	return f
+}
+
+// Test reports whether data is (probably) in the filter: true if every
+// counter at its locations is non-zero.
+func (f *CountingFilter) Test(data []byte) bool {
+	for _, loc := range f.locations(data) {
+		if f.get(loc) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Count estimates data's multiplicity as the minimum counter value across
+// its k locations, the standard lower-bound estimator for counting Bloom
+// filters.
+func (f *CountingFilter) Count(data []byte) uint {
+	min := uint(maxCounterValue)
+	for _, loc := range f.locations(data) {
+		if c := uint(f.get(loc)); c < min {
+			min = c
+		}
+	}
+	return min
+}
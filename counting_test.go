@@ -0,0 +1,45 @@
+package bloom
+
+import "testing"
+
+func TestCountingFilterAddTestRemove(t *testing.T) {
+	f := NewCountingWithEstimates(1000, 0.01)
+
+	f.Add([]byte("foo"))
+	if !f.Test([]byte("foo")) {
+		t.Fatalf("expected foo to test true after being added")
+	}
+	if f.Test([]byte("bar")) {
+		t.Fatalf("did not expect bar to test true")
+	}
+
+	f.Remove([]byte("foo"))
+	if f.Test([]byte("foo")) {
+		t.Fatalf("expected foo to test false after being removed")
+	}
+}
+
+func TestCountingFilterCount(t *testing.T) {
+	f := NewCounting(1024, 4)
+
+	f.Add([]byte("foo"))
+	f.Add([]byte("foo"))
+	f.Add([]byte("foo"))
+
+	if got := f.Count([]byte("foo")); got != 3 {
+		t.Fatalf("expected count 3, got %d", got)
+	}
+	if got := f.Count([]byte("never-added")); got != 0 {
+		t.Fatalf("expected count 0 for item never added, got %d", got)
+	}
+}
+
+func TestCountingFilterSaturates(t *testing.T) {
+	f := NewCounting(1024, 4)
+	for i := 0; i < 100; i++ {
+		f.Add([]byte("saturating"))
+	}
+	if got := f.Count([]byte("saturating")); got != maxCounterValue {
+		t.Fatalf("expected count to saturate at %d, got %d", maxCounterValue, got)
+	}
+}
@@ -0,0 +1,28 @@
+package bloom
+
+import "errors"
+
+var errInvalidEncoding = errors.New("bloom: invalid encoding")
+var errUnsupportedVersion = errors.New("bloom: unsupported encoding version")
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	return append(buf,
+		byte(v), byte(v>>8), byte(v>>16), byte(v>>24),
+		byte(v>>32), byte(v>>40), byte(v>>48), byte(v>>56),
+	)
+}
+
+func readUint32(buf []byte) (uint32, []byte) {
+	v := uint32(buf[0]) | uint32(buf[1])<<8 | uint32(buf[2])<<16 | uint32(buf[3])<<24
+	return v, buf[4:]
+}
+
+func readUint64(buf []byte) (uint64, []byte) {
+	v := uint64(buf[0]) | uint64(buf[1])<<8 | uint64(buf[2])<<16 | uint64(buf[3])<<24 |
+		uint64(buf[4])<<32 | uint64(buf[5])<<40 | uint64(buf[6])<<48 | uint64(buf[7])<<56
+	return v, buf[8:]
+}
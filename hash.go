@@ -0,0 +1,174 @@
+package bloom
+
+// Hasher is the interface Filter uses to derive the four 64-bit hash lanes
+// it needs per item. The package's default Hasher is a built-in MurmurHash3
+// x64_128 implementation, but callers can plug in xxhash, CityHash,
+// SipHash, or any other 128-bit hash via NewWithHasher.
+type Hasher interface {
+	// Sum128 returns a 128-bit hash of data as two 64-bit lanes.
+	Sum128(data []byte) (uint64, uint64)
+}
+
+// defaultHasher is the Hasher used by New, NewWithEstimates, and the
+// package-level baseHashes helper.
+var defaultHasher Hasher = murmur3Hasher{}
+
+// murmur3Hasher is the package's built-in Hasher: MurmurHash3_x64_128, the
+// 128-bit x64 variant of Austin Appleby's MurmurHash3, reimplemented here so
+// the package needs no external hashing dependency.
+type murmur3Hasher struct{}
+
+func (murmur3Hasher) Sum128(data []byte) (uint64, uint64) {
+	return murmurHash3x64128(data, 0)
+}
+
+// hashesFor derives the four 64-bit lanes baseHashes needs from a Hasher
+// that only produces two: it hashes data, then hashes data with a single
+// byte appended, giving two independent-enough 128-bit hashes to draw four
+// lanes from.
+func hashesFor(h Hasher, data []byte) [4]uint64 {
+	v1, v2 := h.Sum128(data)
+
+	tail := make([]byte, len(data)+1)
+	copy(tail, data)
+	tail[len(data)] = 1
+	v3, v4 := h.Sum128(tail)
+
+	return [4]uint64{v1, v2, v3, v4}
+}
+
+// baseHashes returns the four 64-bit lanes derived from the package's
+// built-in MurmurHash3 implementation, used to derive an arbitrary number
+// of locations via double hashing (Kirsch/Mitzenmacher).
+func baseHashes(data []byte) [4]uint64 {
+	return hashesFor(defaultHasher, data)
+}
+
+const (
+	murmurC1 = 0x87c37b91114253d5
+	murmurC2 = 0x4cf5ad432745937f
+)
+
+// murmurHash3x64128 implements MurmurHash3_x64_128: 16-byte blocks are
+// mixed into two accumulators h1, h2 via rotate/multiply/add, any remaining
+// tail bytes are folded in the same way, the data length is mixed in, and
+// both accumulators are run through the finalizer (xor-shift; multiply
+// 0xff51afd7ed558ccd; xor-shift; multiply 0xc4ceb9fe1a85ec53; xor-shift)
+// before a final cross-mix.
+func murmurHash3x64128(data []byte, seed uint64) (uint64, uint64) {
+	h1, h2 := seed, seed
+	nblocks := len(data) / 16
+
+	for i := 0; i < nblocks; i++ {
+		block := data[i*16 : i*16+16]
+		k1 := leUint64(block[0:8])
+		k2 := leUint64(block[8:16])
+
+		k1 *= murmurC1
+		k1 = rotl64(k1, 31)
+		k1 *= murmurC2
+		h1 ^= k1
+
+		h1 = rotl64(h1, 27)
+		h1 += h2
+		h1 = h1*5 + 0x52dce729
+
+		k2 *= murmurC2
+		k2 = rotl64(k2, 33)
+		k2 *= murmurC1
+		h2 ^= k2
+
+		h2 = rotl64(h2, 31)
+		h2 += h1
+		h2 = h2*5 + 0x38495ab5
+	}
+
+	var k1, k2 uint64
+	tail := data[nblocks*16:]
+	switch len(tail) {
+	case 15:
+		k2 ^= uint64(tail[14]) << 48
+		fallthrough
+	case 14:
+		k2 ^= uint64(tail[13]) << 40
+		fallthrough
+	case 13:
+		k2 ^= uint64(tail[12]) << 32
+		fallthrough
+	case 12:
+		k2 ^= uint64(tail[11]) << 24
+		fallthrough
+	case 11:
+		k2 ^= uint64(tail[10]) << 16
+		fallthrough
+	case 10:
+		k2 ^= uint64(tail[9]) << 8
+		fallthrough
+	case 9:
+		k2 ^= uint64(tail[8])
+		k2 *= murmurC2
+		k2 = rotl64(k2, 33)
+		k2 *= murmurC1
+		h2 ^= k2
+		fallthrough
+	case 8:
+		k1 ^= uint64(tail[7]) << 56
+		fallthrough
+	case 7:
+		k1 ^= uint64(tail[6]) << 48
+		fallthrough
+	case 6:
+		k1 ^= uint64(tail[5]) << 40
+		fallthrough
+	case 5:
+		k1 ^= uint64(tail[4]) << 32
+		fallthrough
+	case 4:
+		k1 ^= uint64(tail[3]) << 24
+		fallthrough
+	case 3:
+		k1 ^= uint64(tail[2]) << 16
+		fallthrough
+	case 2:
+		k1 ^= uint64(tail[1]) << 8
+		fallthrough
+	case 1:
+		k1 ^= uint64(tail[0])
+		k1 *= murmurC1
+		k1 = rotl64(k1, 31)
+		k1 *= murmurC2
+		h1 ^= k1
+	}
+
+	h1 ^= uint64(len(data))
+	h2 ^= uint64(len(data))
+
+	h1 += h2
+	h2 += h1
+
+	h1 = fmix64(h1)
+	h2 = fmix64(h2)
+
+	h1 += h2
+	h2 += h1
+
+	return h1, h2
+}
+
+func fmix64(k uint64) uint64 {
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return k
+}
+
+func rotl64(x uint64, r uint) uint64 {
+	return (x << r) | (x >> (64 - r))
+}
+
+func leUint64(b []byte) uint64 {
+	return uint64(b[0]) | uint64(b[1])<<8 | uint64(b[2])<<16 | uint64(b[3])<<24 |
+		uint64(b[4])<<32 | uint64(b[5])<<40 | uint64(b[6])<<48 | uint64(b[7])<<56
+}
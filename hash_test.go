@@ -0,0 +1,49 @@
+package bloom
+
+import (
+	"testing"
+
+	"github.com/spaolacci/murmur3"
+)
+
+// TestMurmurHash3Parity checks that the in-package MurmurHash3_x64_128
+// reimplementation produces exactly the same output as the external
+// github.com/spaolacci/murmur3 package, across a range of input sizes that
+// exercise every tail-length branch.
+func TestMurmurHash3Parity(t *testing.T) {
+	for n := 0; n <= 32; n++ {
+		data := make([]byte, n)
+		for i := range data {
+			data[i] = byte(i * 7)
+		}
+
+		wantV1, wantV2 := murmur3.Sum128(data)
+		gotV1, gotV2 := murmurHash3x64128(data, 0)
+
+		if gotV1 != wantV1 || gotV2 != wantV2 {
+			t.Errorf("len=%d: got (%#x, %#x), want (%#x, %#x)", n, gotV1, gotV2, wantV1, wantV2)
+		}
+	}
+}
+
+func TestNewWithHasher(t *testing.T) {
+	f := NewWithHasher(1024, 4, murmur3Hasher{})
+	f.Add([]byte("plugged-in hasher"))
+	if !f.Test([]byte("plugged-in hasher")) {
+		t.Fatalf("expected item to test true after being added")
+	}
+}
+
+func BenchmarkMurmurHash3InPackage(b *testing.B) {
+	data := []byte("benchmark input for murmur hash parity")
+	for n := 0; n < b.N; n++ {
+		murmurHash3x64128(data, 0)
+	}
+}
+
+func BenchmarkMurmurHash3External(b *testing.B) {
+	data := []byte("benchmark input for murmur hash parity")
+	for n := 0; n < b.N; n++ {
+		murmur3.Sum128(data)
+	}
+}
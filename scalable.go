@@ -0,0 +1,87 @@
+package bloom
+
+import (
+	"math"
+)
+
+// Default growth parameters for ScalableFilter, following Almeida et al.,
+// "Scalable Bloom Filters": each new generation is scalableGrowthFactor
+// times larger than the last and targets a false positive rate
+// scalableTighteningRatio times tighter, so the compounded false positive
+// rate across every generation still converges.
+const (
+	scalableGrowthFactor    = 2.0
+	scalableTighteningRatio = 0.8
+	scalableFillThreshold   = 0.5
+)
+
+// A ScalableFilter is a Bloom filter that grows to accommodate more items
+// than it was initially sized for, addressing the classic complaint that a
+// Filter's capacity is fixed at construction. It holds a sequence of inner
+// Filters, each larger than the last and targeting a tighter false positive
+// rate, rolling over to a new inner filter once the current one nears its
+// target fill.
+type ScalableFilter struct {
+	fp         float64 // the initial, and loosest, target false positive rate
+	n          uint    // initial capacity estimate; later generations scale from it
+	filters    []*Filter
+	capacities []uint // target item count for each entry in filters
+}
+
+// NewScalable creates a ScalableFilter that initially targets n items at a
+// false positive rate of fp. It grows automatically as more items are
+// added.
+func NewScalable(n uint, fp float64) *ScalableFilter {
+	s := &ScalableFilter{fp: fp, n: n}
+	s.addGeneration()
+	return s
+}
+
+func (s *ScalableFilter) addGeneration() {
+	gen := len(s.filters)
+	fp := s.fp * math.Pow(scalableTighteningRatio, float64(gen))
+	n := uint(float64(s.n) * math.Pow(scalableGrowthFactor, float64(gen)))
+	if n < 1 {
+		n = 1
+	}
+	s.filters = append(s.filters, NewWithEstimates(n, fp))
+	s.capacities = append(s.capacities, n)
+}
+
+// full reports whether the newest generation's item count has reached
+// scalableFillThreshold (half) of the capacity it was sized for. Comparing
+// the item count against that capacity is O(1), unlike estimating fill from
+// the bit array itself.
+func (s *ScalableFilter) full() bool {
+	i := len(s.filters) - 1
+	target := float64(s.capacities[i]) * scalableFillThreshold
+	return float64(s.filters[i].N()) >= target
+}
+
+// Add inserts data into the newest inner filter, rolling over to a new,
+// larger generation first if the current one has filled up. It returns s so
+// calls can be chained.
+func (s *ScalableFilter) Add(data []byte) *ScalableFilter {
+	if s.full() {
+		s.addGeneration()
+	}
+	s.filters[len(s.filters)-1].Add(data)
+	return s
+}
+
+// Test reports whether data is (probably) in the filter: true if any
+// generation's inner filter tests true for it.
+func (s *ScalableFilter) Test(data []byte) bool {
+	for _, f := range s.filters {
+		if f.Test(data) {
+			return true
+		}
+	}
+	return false
+}
+
+// Generations returns the number of inner filters the ScalableFilter has
+// grown to.
+func (s *ScalableFilter) Generations() int {
+	return len(s.filters)
+}
@@ -0,0 +1,37 @@
+package bloom
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestScalableFilterBasic(t *testing.T) {
+	f := NewScalable(100, 0.01)
+
+	f.Add([]byte("alpha"))
+	if !f.Test([]byte("alpha")) {
+		t.Fatalf("expected alpha to test true after being added")
+	}
+	if f.Test([]byte("never added")) {
+		t.Fatalf("did not expect unrelated item to test true")
+	}
+}
+
+func TestScalableFilterGrows(t *testing.T) {
+	f := NewScalable(8, 0.01)
+
+	for i := 0; i < 10000; i++ {
+		f.Add([]byte(fmt.Sprintf("item-%d", i)))
+	}
+
+	if f.Generations() <= 1 {
+		t.Fatalf("expected filter to have grown past its first generation, got %d", f.Generations())
+	}
+
+	for i := 0; i < 10000; i++ {
+		item := []byte(fmt.Sprintf("item-%d", i))
+		if !f.Test(item) {
+			t.Fatalf("expected %s to test true across all generations", item)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package bloom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const filterMagic = "BLM1"
+const filterVersion = uint32(1)
+
+// MarshalBinary encodes the filter into a versioned binary format: 4-byte
+// magic "BLM1", uint32 version, uint64 m, uint32 k, uint64 n (count of
+// items added), then the packed bit array as little-endian uint64 words,
+// with any trailing bits beyond m masked to zero.
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, 4+4+8+4+8+len(f.b)*8)
+	buf = append(buf, filterMagic...)
+	buf = appendUint32(buf, filterVersion)
+	buf = appendUint64(buf, uint64(f.m))
+	buf = appendUint32(buf, uint32(f.k))
+	buf = appendUint64(buf, f.n)
+
+	words := make([]uint64, len(f.b))
+	copy(words, f.b)
+	if rem := f.m % 64; rem != 0 && len(words) > 0 {
+		words[len(words)-1] &= (1 << rem) - 1
+	}
+	for _, w := range words {
+		buf = appendUint64(buf, w)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a filter previously encoded with MarshalBinary.
+func (f *Filter) UnmarshalBinary(data []byte) error {
+	if len(data) < 4+4+8+4+8 || string(data[:4]) != filterMagic {
+		return errInvalidEncoding
+	}
+	data = data[4:]
+	version, data := readUint32(data)
+	if version != filterVersion {
+		return errUnsupportedVersion
+	}
+	m, data := readUint64(data)
+	k, data := readUint32(data)
+	n, data := readUint64(data)
+
+	if m == 0 || k == 0 {
+		return errInvalidEncoding
+	}
+
+	wordCount := (uint(m) + 63) / 64
+	if uint64(len(data)) != uint64(wordCount)*8 {
+		return errInvalidEncoding
+	}
+
+	f.m = uint(m)
+	f.k = uint(k)
+	f.n = n
+	f.hasher = defaultHasher
+	f.b = make([]uint64, wordCount)
+	for i := range f.b {
+		var w uint64
+		w, data = readUint64(data)
+		f.b[i] = w
+	}
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder using the same format as MarshalBinary.
+func (f *Filter) GobEncode() ([]byte, error) {
+	return f.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder using the same format as
+// UnmarshalBinary.
+func (f *Filter) GobDecode(data []byte) error {
+	return f.UnmarshalBinary(data)
+}
+
+// WriteTo writes the filter to stream in the MarshalBinary format,
+// implementing io.WriterTo.
+func (f *Filter) WriteTo(stream io.Writer) (int64, error) {
+	data, err := f.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := stream.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom reads a filter previously written with WriteTo, implementing
+// io.ReaderFrom.
+func (f *Filter) ReadFrom(stream io.Reader) (int64, error) {
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	if err := f.UnmarshalBinary(data); err != nil {
+		return int64(len(data)), err
+	}
+	return int64(len(data)), nil
+}
+
+// filterJSON is the on-the-wire JSON representation of a Filter.
+type filterJSON struct {
+	M uint     `json:"m"`
+	K uint     `json:"k"`
+	N uint64   `json:"n"`
+	B []uint64 `json:"b"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (f *Filter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(filterJSON{M: f.m, K: f.k, N: f.n, B: f.b})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (f *Filter) UnmarshalJSON(data []byte) error {
+	var aux filterJSON
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.M == 0 || aux.K == 0 || uint(len(aux.B)) != (aux.M+63)/64 {
+		return errInvalidEncoding
+	}
+	f.m = aux.M
+	f.k = aux.K
+	f.n = aux.N
+	f.b = aux.B
+	f.hasher = defaultHasher
+	return nil
+}
+
+// Merge ORs other's bits into f, so that f.Test reports true for anything
+// that tested true in either filter. Both filters must have the same m and
+// k; otherwise Merge returns an error rather than producing a filter with a
+// meaningless false-positive rate.
+func (f *Filter) Merge(other *Filter) error {
+	if f.m != other.m || f.k != other.k {
+		return fmt.Errorf("bloom: cannot merge filters with different m/k (%d/%d vs %d/%d)", f.m, f.k, other.m, other.k)
+	}
+	for i, w := range other.b {
+		f.b[i] |= w
+	}
+	f.n += other.n
+	return nil
+}
+
+// Equal reports whether f and other have the same m, k, and bit contents.
+// It does not compare the item counts returned by N, since two filters can
+// hold identical bits after different numbers of Add calls (e.g. due to
+// hash collisions or duplicate items).
+func (f *Filter) Equal(other *Filter) bool {
+	if other == nil || f.m != other.m || f.k != other.k || len(f.b) != len(other.b) {
+		return false
+	}
+	for i, w := range f.b {
+		if w != other.b[i] {
+			return false
+		}
+	}
+	return true
+}
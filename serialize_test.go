@@ -0,0 +1,149 @@
+package bloom
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+)
+
+func TestFilterBinaryRoundTrip(t *testing.T) {
+	f := NewWithEstimates(1000, 0.01)
+	f.Add([]byte("alpha"))
+	f.Add([]byte("beta"))
+
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	var g Filter
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+
+	if !f.Equal(&g) {
+		t.Fatalf("expected round-tripped filter to equal original")
+	}
+	if !g.Test([]byte("alpha")) || !g.Test([]byte("beta")) {
+		t.Fatalf("expected round-tripped filter to test true for added items")
+	}
+}
+
+func TestFilterUnmarshalBinaryTruncated(t *testing.T) {
+	f := NewWithEstimates(1000, 0.01)
+	f.Add([]byte("alpha"))
+
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	var g Filter
+	if err := g.UnmarshalBinary(data[:len(data)-4]); err != errInvalidEncoding {
+		t.Fatalf("expected errInvalidEncoding for truncated data, got %v", err)
+	}
+}
+
+func TestFilterUnmarshalBinaryZeroM(t *testing.T) {
+	buf := make([]byte, 0, 4+4+8+4+8)
+	buf = append(buf, filterMagic...)
+	buf = appendUint32(buf, filterVersion)
+	buf = appendUint64(buf, 0) // m = 0
+	buf = appendUint32(buf, 3) // k = 3
+	buf = appendUint64(buf, 0) // n = 0
+
+	var g Filter
+	if err := g.UnmarshalBinary(buf); err != errInvalidEncoding {
+		t.Fatalf("expected errInvalidEncoding for m == 0, got %v", err)
+	}
+}
+
+func TestFilterUnmarshalJSONValidatesDimensions(t *testing.T) {
+	var g Filter
+	if err := g.UnmarshalJSON([]byte(`{"m":1000,"k":3,"n":0,"b":[]}`)); err != errInvalidEncoding {
+		t.Fatalf("expected errInvalidEncoding for mismatched bit-word count, got %v", err)
+	}
+
+	if err := g.UnmarshalJSON([]byte(`{"m":0,"k":3,"n":0,"b":[]}`)); err != errInvalidEncoding {
+		t.Fatalf("expected errInvalidEncoding for m == 0, got %v", err)
+	}
+
+	if err := g.UnmarshalJSON([]byte(`{"m":64,"k":0,"n":0,"b":[0]}`)); err != errInvalidEncoding {
+		t.Fatalf("expected errInvalidEncoding for k == 0, got %v", err)
+	}
+}
+
+func TestFilterWriteToReadFrom(t *testing.T) {
+	f := NewWithEstimates(1000, 0.01)
+	f.Add([]byte("gamma"))
+
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	var g Filter
+	if _, err := g.ReadFrom(&buf); err != nil {
+		t.Fatalf("ReadFrom: %v", err)
+	}
+	if !f.Equal(&g) {
+		t.Fatalf("expected round-tripped filter to equal original")
+	}
+}
+
+func TestFilterGobRoundTrip(t *testing.T) {
+	f := NewWithEstimates(1000, 0.01)
+	f.Add([]byte("delta"))
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(f); err != nil {
+		t.Fatalf("gob encode: %v", err)
+	}
+
+	var g Filter
+	if err := gob.NewDecoder(&buf).Decode(&g); err != nil {
+		t.Fatalf("gob decode: %v", err)
+	}
+	if !f.Equal(&g) {
+		t.Fatalf("expected round-tripped filter to equal original")
+	}
+}
+
+func TestFilterJSONRoundTrip(t *testing.T) {
+	f := NewWithEstimates(1000, 0.01)
+	f.Add([]byte("epsilon"))
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	var g Filter
+	if err := json.Unmarshal(data, &g); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if !f.Equal(&g) {
+		t.Fatalf("expected round-tripped filter to equal original")
+	}
+}
+
+func TestFilterMerge(t *testing.T) {
+	a := NewWithEstimates(1000, 0.01)
+	a.Add([]byte("one"))
+
+	b := NewWithEstimates(1000, 0.01)
+	b.Add([]byte("two"))
+
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if !a.Test([]byte("one")) || !a.Test([]byte("two")) {
+		t.Fatalf("expected merged filter to test true for items from both inputs")
+	}
+
+	mismatched := New(64, 4)
+	if err := a.Merge(mismatched); err == nil {
+		t.Fatalf("expected Merge to error on mismatched m/k")
+	}
+}
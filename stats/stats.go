@@ -0,0 +1,118 @@
+// Package stats provides small statistical helpers used by this module's
+// hash-uniformity tests, factored out of the test files so the chi-squared
+// machinery can be reused and unit tested on its own.
+package stats
+
+import "math"
+
+// ChiSquareResult is the structured result of a chi-squared goodness-of-fit
+// test against a uniform distribution.
+type ChiSquareResult struct {
+	Statistic float64
+	DF        float64
+	PValue    float64
+	Buckets   []uint
+	Expected  float64
+}
+
+// ChiSquare runs a chi-squared goodness-of-fit test comparing observed
+// bucket counts against a uniform distribution where every bucket is
+// expected to receive expected hits. DF is len(observed)-1.
+func ChiSquare(observed []uint, expected float64) ChiSquareResult {
+	var statistic float64
+	for _, o := range observed {
+		d := float64(o) - expected
+		statistic += d * d / expected
+	}
+	df := float64(len(observed) - 1)
+	return ChiSquareResult{
+		Statistic: statistic,
+		DF:        df,
+		PValue:    ChiSquarePValue(df, statistic),
+		Buckets:   observed,
+		Expected:  expected,
+	}
+}
+
+// ChiSquarePValue returns P(X > statistic) for a chi-squared distribution
+// with df degrees of freedom, i.e. the probability of seeing a statistic at
+// least this large if the buckets really were drawn from a uniform
+// distribution. It is computed from the regularized incomplete gamma
+// function: p = 1 - P(df/2, statistic/2).
+func ChiSquarePValue(df, statistic float64) float64 {
+	if statistic <= 0 {
+		return 1
+	}
+	return 1 - regularizedLowerIncompleteGamma(df/2, statistic/2)
+}
+
+// regularizedLowerIncompleteGamma computes P(a, x), the regularized lower
+// incomplete gamma function, following the classic Numerical Recipes
+// approach: a series expansion for x < a+1, and a continued fraction
+// (evaluated as its complement, Q(a,x) = 1 - P(a,x)) otherwise.
+func regularizedLowerIncompleteGamma(a, x float64) float64 {
+	if x < 0 || a <= 0 {
+		return math.NaN()
+	}
+	if x == 0 {
+		return 0
+	}
+	if x < a+1 {
+		return gammaSeries(a, x)
+	}
+	return 1 - gammaContinuedFraction(a, x)
+}
+
+const (
+	gammaMaxIterations = 200
+	gammaEpsilon       = 3e-12
+	gammaFPMin         = 1e-300
+)
+
+// gammaSeries evaluates P(a, x) via its series representation; valid for
+// x < a+1, where it converges quickly.
+func gammaSeries(a, x float64) float64 {
+	gln, _ := math.Lgamma(a)
+	ap := a
+	sum := 1 / a
+	del := sum
+	for n := 0; n < gammaMaxIterations; n++ {
+		ap++
+		del *= x / ap
+		sum += del
+		if math.Abs(del) < math.Abs(sum)*gammaEpsilon {
+			break
+		}
+	}
+	return sum * math.Exp(-x+a*math.Log(x)-gln)
+}
+
+// gammaContinuedFraction evaluates Q(a, x) = 1 - P(a, x) via Lentz's
+// continued-fraction method; valid for x >= a+1, where the series above
+// converges too slowly to be useful.
+func gammaContinuedFraction(a, x float64) float64 {
+	gln, _ := math.Lgamma(a)
+	b := x + 1 - a
+	c := 1 / gammaFPMin
+	d := 1 / b
+	h := d
+	for i := 1; i <= gammaMaxIterations; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < gammaFPMin {
+			d = gammaFPMin
+		}
+		c = b + an/c
+		if math.Abs(c) < gammaFPMin {
+			c = gammaFPMin
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < gammaEpsilon {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-gln) * h
+}
@@ -0,0 +1,32 @@
+package stats
+
+import "testing"
+
+func TestChiSquareUniform(t *testing.T) {
+	// Perfectly uniform buckets should yield a statistic of zero and a
+	// p-value of 1.
+	observed := []uint{10, 10, 10, 10}
+	r := ChiSquare(observed, 10)
+	if r.Statistic != 0 {
+		t.Fatalf("expected statistic 0, got %v", r.Statistic)
+	}
+	if r.PValue != 1 {
+		t.Fatalf("expected p-value 1, got %v", r.PValue)
+	}
+	if r.DF != 3 {
+		t.Fatalf("expected df 3, got %v", r.DF)
+	}
+}
+
+func TestChiSquareSkewed(t *testing.T) {
+	// A wildly skewed distribution should produce a large statistic and a
+	// small p-value.
+	observed := []uint{1000, 0, 0, 0}
+	r := ChiSquare(observed, 250)
+	if r.Statistic <= 0 {
+		t.Fatalf("expected statistic > 0, got %v", r.Statistic)
+	}
+	if r.PValue > 0.001 {
+		t.Fatalf("expected p-value <= 0.001, got %v", r.PValue)
+	}
+}
@@ -1,13 +1,30 @@
 package bloom
 
 import (
-	"testing"
-	"github.com/spaolacci/murmur3"
+	"flag"
 	"fmt"
-	"math"
 	"hash/fnv"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spaolacci/murmur3"
+	"github.com/willf/bloom/stats"
 )
 
+// hashesFlag restricts TestBloomLocationUniformity to a comma-separated
+// subset of its hash location functions (bloom, murmur, wfnv, fnv, fnva,
+// counting, scalable).
+// An empty value (the default) runs all of them.
+var hashesFlag string
+
+func TestMain(m *testing.M) {
+	testing.Init()
+	flag.StringVar(&hashesFlag, "hashes", "", "comma-separated list of hash location functions to run (default: all)")
+	flag.Parse()
+	os.Exit(m.Run())
+}
+
 // I set this up as a type so that I can pass functions into a tester.
 type locations func(data []byte, k, m uint32) []uint
 
@@ -94,9 +111,70 @@ func murmurLocations(data []byte, k, m uint32) []uint {
 }
 
 
+// countingLocations reproduces the location math CountingFilter uses
+// internally, via the same shared location/hashesFor helpers Filter uses,
+// so its uniformity can be checked here without allocating a counter array
+// per call.
+func countingLocations(data []byte, k, m uint32) []uint {
+	h := hashesFor(defaultHasher, data)
+	locs := make([]uint, k)
+	for i := uint32(0); i < k; i++ {
+		locs[i] = location(h, uint(i), uint(m))
+	}
+	return locs
+}
+
+// scalableLocations reproduces the location math used inside any one
+// generation of a ScalableFilter: each generation is an ordinary Filter, so
+// it shares the exact same location/hashesFor helpers as CountingFilter and
+// Filter itself.
+func scalableLocations(data []byte, k, m uint32) []uint {
+	return countingLocations(data, k, m)
+}
+
+// hashCase names one of the location functions above so it can be selected
+// via -hashes and reported by name.
+type hashCase struct {
+	name string
+	fn   locations
+}
+
+var hashCases = []hashCase{
+	{"bloom", bloomLocations},
+	{"murmur", murmurLocations},
+	{"wfnv", wfnvLocations},
+	{"fnv", fnvLocations},
+	{"fnva", fnvaLocations},
+	{"counting", countingLocations},
+	{"scalable", scalableLocations},
+}
+
+// selectedHashCases returns the subset of hashCases named in flagVal (a
+// comma-separated list), or all of them if flagVal is empty.
+func selectedHashCases(flagVal string) []hashCase {
+	if flagVal == "" {
+		return hashCases
+	}
+	want := make(map[string]bool)
+	for _, name := range strings.Split(flagVal, ",") {
+		want[strings.TrimSpace(name)] = true
+	}
+	var selected []hashCase
+	for _, c := range hashCases {
+		if want[c.name] {
+			selected = append(selected, c)
+		}
+	}
+	return selected
+}
+
 // The test runs many rounds, and the filter's m and k are easily adjustable.
-// It builds a set of raw input data that it can re-use, which is faster but 
+// It builds a set of raw input data that it can re-use, which is faster but
 // uses more memory.
+//
+// Each hash's locations are checked for uniformity with a chi-squared
+// goodness-of-fit test; a result whose p-value drops below 0.001 fails the
+// test outright rather than being left for a human to eyeball.
 func TestBloomLocationUniformity(t *testing.T) {
 
 	var m, k, rounds uint32
@@ -117,28 +195,25 @@ func TestBloomLocationUniformity(t *testing.T) {
 		data := []byte(ctrlist)
 		elements[x] = data
 	}
-	// fmt.Println(elements)
-
-	fmt.Println("Willf Bloom w/4 hashes")
-	chiTestBloom(m, k, rounds, elements, bloomLocations)
-	fmt.Println("")
-
-	fmt.Println("Murmur3 128-bit split to 2 64-bit hashes")
-	chiTestBloom(m, k, rounds, elements, murmurLocations)
-	fmt.Println("")
 
-	fmt.Println("Willf Bloom w/2 hashes")
-	chiTestBloom(m, k, rounds, elements, wfnvLocations)
-	fmt.Println("")
-
-	fmt.Println("FNV-1 64-bit split to 2 32-bit hashes")
-	chiTestBloom(m, k, rounds, elements, fnvLocations)
-	fmt.Println("")
-
-	fmt.Println("FNV-1a 64-bit split to 2 32-bit hashes")
-	chiTestBloom(m, k, rounds, elements, fnvLocations)
-	fmt.Println("")
+	for _, c := range selectedHashCases(hashesFlag) {
+		result := chiTestBloom(m, k, rounds, elements, c.fn)
+		reportChiSquareResult(c.name, rounds, result)
+		if result.PValue < 0.001 {
+			t.Fatalf("%s: uniformity regression: chi2=%.4f df=%.0f p=%.6f (want p >= 0.001)",
+				c.name, result.Statistic, result.DF, result.PValue)
+		}
+	}
+}
 
+// reportChiSquareResult prints one result line in a machine-parseable
+// format similar to `go test -bench` output, so benchstat-style tooling can
+// diff uniformity results across runs, e.g.:
+//
+//	UniformityChiSquare/bloom	15000000	chi2=3.2100	df=7	p=0.864920
+func reportChiSquareResult(name string, rounds uint32, r stats.ChiSquareResult) {
+	fmt.Printf("UniformityChiSquare/%s\t%d\tchi2=%.4f\tdf=%.0f\tp=%.6f\n",
+		name, rounds, r.Statistic, r.DF, r.PValue)
 }
 
 /*
@@ -163,12 +238,9 @@ Turns out that isn't necessary since some of the results produce a test
 statistic so large.
 
 */
-func chiTestBloom(m, k, rounds uint32, elements [][]byte, fLoc locations) {
+func chiTestBloom(m, k, rounds uint32, elements [][]byte, fLoc locations) stats.ChiSquareResult {
 
 	results := make([]uint, m)
-	chi := make([]float64, m)
-
-	var chi_statistic float64
 
 	for _, data := range elements {
 		for _, loc := range fLoc(data, k, m) {
@@ -178,15 +250,8 @@ func chiTestBloom(m, k, rounds uint32, elements [][]byte, fLoc locations) {
 
 	// Each element of results should contain the same value: k * rounds / m.
 	// Let's run a chi-square goodness of fit and see how it fares.
-	e := float64(k * rounds) / float64(m)
-	for i := uint32(0); i < m; i++ {
-		chi[i] = math.Pow(float64(results[i]) - e, 2.0) / e
-		chi_statistic += chi[i]
-	}
-
-	fmt.Println(results)
-	fmt.Println(chi)
-	fmt.Println(chi_statistic)
+	e := float64(k*rounds) / float64(m)
+	return stats.ChiSquare(results, e)
 }
 
 func BenchmarkBloomLocations(b *testing.B) {